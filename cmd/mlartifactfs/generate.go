@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jakeBringetto/MLArtifactFS/pkg/manifest"
+)
+
+// runGenerate implements `mlartifactfs generate`: walk -src and write a
+// manifest describing it to -out.
+func runGenerate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+
+	src := fs.String("src", "", "directory to walk (required)")
+	artifactID := fs.String("artifact-id", "", "artifact identifier, e.g. llama-7b (required)")
+	version := fs.String("version", "", "artifact version, e.g. v1.0 (required)")
+	urlPrefix := fs.String("url-prefix", "", "storage URL prefix; see ResolveBackend for supported schemes (required)")
+	out := fs.String("out", "manifest.json", "path to write the manifest to")
+
+	chunkSize := fs.Int64("chunk-size", 0, "enable content-defined chunking with this target chunk size in bytes (0 disables chunking)")
+	minChunk := fs.Int64("min-chunk", 0, "minimum chunk size in bytes; defaults to a fraction of -chunk-size")
+	maxChunk := fs.Int64("max-chunk", 0, "maximum chunk size in bytes; defaults to a multiple of -chunk-size")
+
+	compression := fs.String("compression", manifest.CompressionNone, "per-file compression codec: zstd, gzip, none, or auto")
+
+	s3PathStyle := fs.Bool("s3-path-style", false, "for s3:// -url-prefix, address the bucket as s3.amazonaws.com/bucket instead of bucket.s3.amazonaws.com (needed for S3-compatible stores that don't support virtual-hosted buckets)")
+
+	jobs := fs.Int("jobs", 0, "max files hashed/chunked/compressed concurrently (0 means GOMAXPROCS)")
+	cachePath := fs.String("cache", manifest.DefaultCacheFileName, "on-disk hash cache to read and update")
+	noCache := fs.Bool("no-cache", false, "rehash every file instead of consulting -cache")
+	verify := fs.Bool("verify", false, "rehash every file even when -cache has an entry, to catch bit rot; the cache is still updated")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *src == "" || *artifactID == "" || *version == "" || *urlPrefix == "" {
+		fs.Usage()
+		return fmt.Errorf("generate: -src, -artifact-id, -version, and -url-prefix are required")
+	}
+
+	opts := manifest.GenerateOptions{}
+	if *chunkSize > 0 {
+		cfg := manifest.DefaultChunkConfig()
+		cfg.TargetSize = *chunkSize
+		if *minChunk > 0 {
+			cfg.MinSize = *minChunk
+		}
+		if *maxChunk > 0 {
+			cfg.MaxSize = *maxChunk
+		}
+		opts.Chunking = cfg
+	}
+	opts.Compression = manifest.CompressionConfig{Mode: *compression}
+	opts.Jobs = *jobs
+	opts.ForceRehash = *verify
+
+	var cache *manifest.Cache
+	if !*noCache {
+		var err error
+		cache, err = manifest.LoadCache(*cachePath)
+		if err != nil {
+			return fmt.Errorf("generate: %w", err)
+		}
+		opts.Cache = cache
+	}
+
+	var (
+		m   *manifest.Manifest
+		err error
+	)
+	if *s3PathStyle {
+		// NewS3Backend's pathStyle option isn't reachable through
+		// ResolveBackend's single-prefix convention, so this is the one
+		// case generate needs GenerateWithBackend instead of -url-prefix.
+		backend, berr := s3PathStyleBackend(*urlPrefix)
+		if berr != nil {
+			return fmt.Errorf("generate: %w", berr)
+		}
+		m, err = manifest.GenerateWithBackend(*src, *artifactID, *version, backend, nil, opts)
+	} else {
+		m, err = manifest.GenerateWithOptions(*src, *artifactID, *version, *urlPrefix, nil, opts)
+	}
+	if err != nil {
+		return fmt.Errorf("generate: %w", err)
+	}
+
+	data, err := manifest.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("generate: %w", err)
+	}
+
+	if err := os.WriteFile(*out, data, 0644); err != nil {
+		return fmt.Errorf("generate: writing %s: %w", *out, err)
+	}
+
+	if cache != nil {
+		if err := cache.Save(*cachePath); err != nil {
+			return fmt.Errorf("generate: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// s3PathStyleBackend parses an "s3://bucket[/key-prefix]" urlPrefix and
+// builds a path-style S3 backend from it, mirroring the bucket/key split
+// ResolveBackend does for its own (virtual-hosted) s3:// case.
+func s3PathStyleBackend(urlPrefix string) (manifest.Backend, error) {
+	rest := strings.TrimPrefix(urlPrefix, "s3://")
+	if rest == urlPrefix {
+		return nil, fmt.Errorf("-s3-path-style requires an s3:// -url-prefix, got %q", urlPrefix)
+	}
+	rest = strings.TrimSuffix(rest, "/")
+	bucket, keyPrefix, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("-url-prefix: s3:// URL missing bucket: %q", urlPrefix)
+	}
+	return manifest.NewS3Backend(bucket, keyPrefix, true), nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// runKeygen implements `mlartifactfs keygen`: generate an ed25519 keypair
+// for `sign`/`verify`, writing the hex-encoded private key to -out and
+// printing the hex-encoded public key (the line to add to an allowlist
+// file) to stdout.
+func runKeygen(args []string) error {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	out := fs.String("out", "mlartifactfs.key", "path to write the hex-encoded private key to")
+	force := fs.Bool("force", false, "overwrite -out if it already exists")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return fmt.Errorf("keygen: %w", err)
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC | os.O_EXCL
+	if *force {
+		flags &^= os.O_EXCL
+	}
+	f, err := os.OpenFile(*out, flags, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("keygen: %s already exists; pass -force to overwrite it", *out)
+		}
+		return fmt.Errorf("keygen: writing %s: %w", *out, err)
+	}
+
+	// O_TRUNC doesn't reset an existing file's mode, so a -force overwrite
+	// of a pre-existing, looser-permissioned file would otherwise leave
+	// the new private key readable by more than its owner.
+	if err := f.Chmod(0600); err != nil {
+		return fmt.Errorf("keygen: %s: %w", *out, err)
+	}
+	if _, err := f.WriteString(hex.EncodeToString(priv) + "\n"); err != nil {
+		return fmt.Errorf("keygen: writing %s: %w", *out, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("keygen: writing %s: %w", *out, err)
+	}
+
+	fmt.Printf("private key written to %s\npublic key (add this line to an allowlist file): %s\n", *out, hex.EncodeToString(pub))
+	return nil
+}
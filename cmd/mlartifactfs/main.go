@@ -0,0 +1,50 @@
+// Command mlartifactfs generates, signs, and verifies MLArtifactFS
+// manifests. See each subcommand's -h output for its flags.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "generate":
+		err = runGenerate(os.Args[2:])
+	case "keygen":
+		err = runKeygen(os.Args[2:])
+	case "sign":
+		err = runSign(os.Args[2:])
+	case "verify":
+		err = runVerify(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		usage()
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "mlartifactfs: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mlartifactfs: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprint(os.Stderr, `usage: mlartifactfs <command> [flags]
+
+Commands:
+  generate   Walk a directory and write a manifest describing it
+  keygen     Generate an ed25519 keypair for sign/verify
+  sign       Write a detached signature over a manifest
+  verify     Check a manifest's signature against a public-key allowlist
+`)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jakeBringetto/MLArtifactFS/pkg/manifest"
+	"github.com/jakeBringetto/MLArtifactFS/pkg/manifest/sign"
+)
+
+// runSign implements `mlartifactfs sign`: load a manifest, sign its
+// canonical form with the ed25519 key at -key, and write the detached
+// signature to -out (defaulting to the conventional sign.SigPath).
+func runSign(args []string) error {
+	fs := flag.NewFlagSet("sign", flag.ExitOnError)
+	manifestPath := fs.String("manifest", "", "manifest file to sign (required)")
+	keyPath := fs.String("key", "", "hex-encoded ed25519 private key file, as written by `mlartifactfs keygen` (required)")
+	out := fs.String("out", "", "path to write the detached signature to (default: <manifest>.sig)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *manifestPath == "" || *keyPath == "" {
+		fs.Usage()
+		return fmt.Errorf("sign: -manifest and -key are required")
+	}
+	if *out == "" {
+		*out = sign.SigPath(*manifestPath)
+	}
+
+	m, err := manifest.Load(*manifestPath)
+	if err != nil {
+		return fmt.Errorf("sign: %w", err)
+	}
+
+	priv, err := loadPrivateKey(*keyPath)
+	if err != nil {
+		return fmt.Errorf("sign: %w", err)
+	}
+
+	sig, err := sign.Sign(m, priv)
+	if err != nil {
+		return fmt.Errorf("sign: %w", err)
+	}
+
+	if err := os.WriteFile(*out, sig, 0644); err != nil {
+		return fmt.Errorf("sign: writing %s: %w", *out, err)
+	}
+
+	return nil
+}
+
+// loadPrivateKey reads a hex-encoded ed25519 private key written by
+// `mlartifactfs keygen`, the CLI's symmetric counterpart to how
+// sign.LoadAllowlist reads hex-encoded public keys.
+func loadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading key %s: %w", path, err)
+	}
+
+	raw, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("key %s: invalid hex: %w", path, err)
+	}
+	if len(raw) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("key %s: expected a %d-byte ed25519 private key, got %d bytes", path, ed25519.PrivateKeySize, len(raw))
+	}
+
+	return ed25519.PrivateKey(raw), nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/jakeBringetto/MLArtifactFS/pkg/manifest/sign"
+)
+
+// runVerify implements `mlartifactfs verify`: load -manifest and its
+// detached signature and reject it unless it verifies against a key in
+// -allowlist — the check a mount client must pass before it touches a FUSE
+// mount at all.
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	manifestPath := fs.String("manifest", "", "manifest file to verify (required)")
+	sigPath := fs.String("sig", "", "detached signature file (default: <manifest>.sig)")
+	allowlistPath := fs.String("allowlist", "", "public-key allowlist file, as read by sign.LoadAllowlist (required)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *manifestPath == "" || *allowlistPath == "" {
+		fs.Usage()
+		return fmt.Errorf("verify: -manifest and -allowlist are required")
+	}
+	if *sigPath == "" {
+		*sigPath = sign.SigPath(*manifestPath)
+	}
+
+	allowlist, err := sign.LoadAllowlist(*allowlistPath)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+
+	if _, err := sign.LoadAndVerify(*manifestPath, *sigPath, allowlist); err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+
+	fmt.Println("OK: signature verifies against the allowlist")
+	return nil
+}
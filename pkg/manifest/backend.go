@@ -0,0 +1,188 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Backend resolves where a file (or, in content-addressed mode, a blob)
+// actually lives so Generate can stay storage-agnostic. relPath is the
+// logical path within the artifact; hash is always the file's SHA256.
+// Implementations that address content by hash (e.g. OCI registries) may
+// ignore relPath entirely; implementations that address by path may ignore
+// hash. Generate passes relPath == "" to request the content-addressed form
+// of the URL.
+type Backend interface {
+	ObjectURL(relPath, hash string) string
+	// Scheme identifies the backend for logging/diagnostics, e.g. "s3", "gs".
+	Scheme() string
+}
+
+// ResolveBackend parses a urlPrefix (e.g. "https://bucket.s3.amazonaws.com/path",
+// "gs://bucket/path", "azure://account/container/path", "oci://registry/repo",
+// "file:///mnt/artifacts") and returns the Backend that knows how to build
+// object URLs for it. This is the single entry point the CLI needs: one
+// --url-prefix flag, any supported scheme.
+func ResolveBackend(prefix string) (Backend, error) {
+	switch {
+	case strings.HasPrefix(prefix, "http://"), strings.HasPrefix(prefix, "https://"):
+		return &httpBackend{prefix: strings.TrimSuffix(prefix, "/")}, nil
+
+	case strings.HasPrefix(prefix, "s3://"):
+		bucket, key := splitHostPath(strings.TrimPrefix(prefix, "s3://"))
+		if bucket == "" {
+			return nil, fmt.Errorf("backend: s3:// URL missing bucket: %q", prefix)
+		}
+		return NewS3Backend(bucket, key, false), nil
+
+	case strings.HasPrefix(prefix, "gs://"):
+		bucket, path := splitHostPath(strings.TrimPrefix(prefix, "gs://"))
+		if bucket == "" {
+			return nil, fmt.Errorf("backend: gs:// URL missing bucket: %q", prefix)
+		}
+		return &gcsBackend{bucket: bucket, path: path}, nil
+
+	case strings.HasPrefix(prefix, "azure://"):
+		account, rest := splitHostPath(strings.TrimPrefix(prefix, "azure://"))
+		container, path := splitHostPath(rest)
+		if account == "" || container == "" {
+			return nil, fmt.Errorf("backend: azure:// URL must be azure://account/container[/path], got %q", prefix)
+		}
+		return &azureBackend{account: account, container: container, path: path}, nil
+
+	case strings.HasPrefix(prefix, "oci://"):
+		registry := strings.TrimSuffix(strings.TrimPrefix(prefix, "oci://"), "/")
+		if registry == "" {
+			return nil, fmt.Errorf("backend: oci:// URL missing registry/repo: %q", prefix)
+		}
+		return &ociBackend{registry: registry}, nil
+
+	case strings.HasPrefix(prefix, "file://"):
+		return &fileBackend{base: strings.TrimSuffix(strings.TrimPrefix(prefix, "file://"), "/")}, nil
+
+	default:
+		return nil, fmt.Errorf("backend: unsupported url-prefix scheme: %q (expected http(s)://, s3://, gs://, azure://, oci://, or file://)", prefix)
+	}
+}
+
+// splitHostPath splits "host/rest/of/path" into ("host", "rest/of/path").
+func splitHostPath(s string) (host, rest string) {
+	s = strings.TrimSuffix(s, "/")
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// objectURLFromPrefix is the shared "bucket + key" URL layout used by the
+// HTTP, GCS, Azure, and file backends: a logical relPath when one is given,
+// or the sharded content-addressed path keyed by hash otherwise.
+func objectURLFromPrefix(prefix, relPath, hash string) string {
+	if relPath != "" {
+		return prefix + "/" + relPath
+	}
+	return prefix + "/sha256/" + hash[:2] + "/" + hash
+}
+
+// httpBackend serves a plain http(s) urlPrefix, the original (and still
+// default) behavior: File.URL is just urlPrefix + relPath.
+type httpBackend struct{ prefix string }
+
+func (b *httpBackend) ObjectURL(relPath, hash string) string {
+	return objectURLFromPrefix(b.prefix, relPath, hash)
+}
+func (b *httpBackend) Scheme() string { return "http" }
+
+// s3Backend builds S3 object URLs in either virtual-hosted
+// ("https://bucket.s3.amazonaws.com/key") or path-style
+// ("https://s3.amazonaws.com/bucket/key") form.
+type s3Backend struct {
+	bucket    string
+	keyPrefix string
+	pathStyle bool
+}
+
+// NewS3Backend constructs a Backend for an S3 bucket. keyPrefix is prepended
+// to every object key (pass "" for none). pathStyle selects
+// "s3.amazonaws.com/bucket/..." URLs instead of the default
+// "bucket.s3.amazonaws.com/..." virtual-hosted form; path-style is mainly
+// useful for S3-compatible stores that don't support virtual-hosted buckets.
+func NewS3Backend(bucket, keyPrefix string, pathStyle bool) Backend {
+	return &s3Backend{bucket: bucket, keyPrefix: strings.Trim(keyPrefix, "/"), pathStyle: pathStyle}
+}
+
+func (b *s3Backend) prefix() string {
+	var host string
+	if b.pathStyle {
+		host = "https://s3.amazonaws.com/" + b.bucket
+	} else {
+		host = "https://" + b.bucket + ".s3.amazonaws.com"
+	}
+	if b.keyPrefix == "" {
+		return host
+	}
+	return host + "/" + b.keyPrefix
+}
+
+func (b *s3Backend) ObjectURL(relPath, hash string) string {
+	return objectURLFromPrefix(b.prefix(), relPath, hash)
+}
+func (b *s3Backend) Scheme() string { return "s3" }
+
+// gcsBackend builds Google Cloud Storage object URLs.
+type gcsBackend struct {
+	bucket string
+	path   string
+}
+
+func (b *gcsBackend) prefix() string {
+	host := "https://storage.googleapis.com/" + b.bucket
+	if b.path == "" {
+		return host
+	}
+	return host + "/" + b.path
+}
+
+func (b *gcsBackend) ObjectURL(relPath, hash string) string {
+	return objectURLFromPrefix(b.prefix(), relPath, hash)
+}
+func (b *gcsBackend) Scheme() string { return "gs" }
+
+// azureBackend builds Azure Blob Storage object URLs.
+type azureBackend struct {
+	account   string
+	container string
+	path      string
+}
+
+func (b *azureBackend) prefix() string {
+	host := fmt.Sprintf("https://%s.blob.core.windows.net/%s", b.account, b.container)
+	if b.path == "" {
+		return host
+	}
+	return host + "/" + b.path
+}
+
+func (b *azureBackend) ObjectURL(relPath, hash string) string {
+	return objectURLFromPrefix(b.prefix(), relPath, hash)
+}
+func (b *azureBackend) Scheme() string { return "azure" }
+
+// ociBackend addresses every object as an OCI blob by digest, since OCI
+// registries have no notion of a logical path: relPath is always ignored.
+type ociBackend struct{ registry string }
+
+func (b *ociBackend) ObjectURL(relPath, hash string) string {
+	return b.registry + "/blobs/sha256:" + hash
+}
+func (b *ociBackend) Scheme() string { return "oci" }
+
+// fileBackend addresses objects on local disk for offline use (no network
+// fetch at mount time; the mount command reads base/relPath directly).
+type fileBackend struct{ base string }
+
+func (b *fileBackend) ObjectURL(relPath, hash string) string {
+	return "file://" + objectURLFromPrefix(b.base, relPath, hash)
+}
+func (b *fileBackend) Scheme() string { return "file" }
@@ -0,0 +1,115 @@
+package manifest
+
+import "testing"
+
+func TestResolveBackend(t *testing.T) {
+	tests := []struct {
+		name       string
+		prefix     string
+		wantErr    bool
+		wantScheme string
+		relPath    string
+		hash       string
+		wantURL    string
+	}{
+		{
+			name:       "https",
+			prefix:     "https://bucket.s3.amazonaws.com/models/v1",
+			wantScheme: "http",
+			relPath:    "config.json",
+			wantURL:    "https://bucket.s3.amazonaws.com/models/v1/config.json",
+		},
+		{
+			name:       "https trailing slash",
+			prefix:     "https://example.com/path/",
+			wantScheme: "http",
+			relPath:    "a.txt",
+			wantURL:    "https://example.com/path/a.txt",
+		},
+		{
+			name:       "s3 scheme",
+			prefix:     "s3://my-bucket/models/v1",
+			wantScheme: "s3",
+			relPath:    "weights.bin",
+			wantURL:    "https://my-bucket.s3.amazonaws.com/models/v1/weights.bin",
+		},
+		{
+			name:       "gcs scheme",
+			prefix:     "gs://my-bucket/models/v1",
+			wantScheme: "gs",
+			relPath:    "weights.bin",
+			wantURL:    "https://storage.googleapis.com/my-bucket/models/v1/weights.bin",
+		},
+		{
+			name:       "azure scheme",
+			prefix:     "azure://myaccount/mycontainer/models/v1",
+			wantScheme: "azure",
+			relPath:    "weights.bin",
+			wantURL:    "https://myaccount.blob.core.windows.net/mycontainer/models/v1/weights.bin",
+		},
+		{
+			name:       "oci scheme ignores relPath",
+			prefix:     "oci://registry.example.com/models/llama",
+			wantScheme: "oci",
+			relPath:    "weights.bin",
+			hash:       "abc123",
+			wantURL:    "registry.example.com/models/llama/blobs/sha256:abc123",
+		},
+		{
+			name:       "file scheme",
+			prefix:     "file:///mnt/artifacts/v1",
+			wantScheme: "file",
+			relPath:    "weights.bin",
+			wantURL:    "file:///mnt/artifacts/v1/weights.bin",
+		},
+		{
+			name:    "unsupported scheme",
+			prefix:  "ftp://example.com/path",
+			wantErr: true,
+		},
+		{
+			name:    "s3 missing bucket",
+			prefix:  "s3://",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, err := ResolveBackend(tt.prefix)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveBackend(%q) error = %v, wantErr %v", tt.prefix, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if backend.Scheme() != tt.wantScheme {
+				t.Errorf("Scheme() = %q, want %q", backend.Scheme(), tt.wantScheme)
+			}
+			if got := backend.ObjectURL(tt.relPath, tt.hash); got != tt.wantURL {
+				t.Errorf("ObjectURL(%q, %q) = %q, want %q", tt.relPath, tt.hash, got, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestS3Backend_PathStyle(t *testing.T) {
+	backend := NewS3Backend("my-bucket", "models/v1", true)
+	got := backend.ObjectURL("weights.bin", "")
+	want := "https://s3.amazonaws.com/my-bucket/models/v1/weights.bin"
+	if got != want {
+		t.Errorf("ObjectURL() = %q, want %q", got, want)
+	}
+}
+
+func TestBackend_ContentAddressedURL(t *testing.T) {
+	backend, err := ResolveBackend("https://example.com/models/v1")
+	if err != nil {
+		t.Fatalf("ResolveBackend failed: %v", err)
+	}
+	got := backend.ObjectURL("", "deadbeef")
+	want := "https://example.com/models/v1/sha256/de/deadbeef"
+	if got != want {
+		t.Errorf("ObjectURL(\"\", hash) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateWithOptions_ContentAddressed(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "a.txt"), []byte("shared content"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "b.txt"), []byte("shared content"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "c.txt"), []byte("different content"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	m, err := GenerateWithOptions(tmpDir, "test", "v1", "https://example.com", nil, GenerateOptions{ContentAddressed: true})
+	if err != nil {
+		t.Fatalf("GenerateWithOptions failed: %v", err)
+	}
+
+	if len(m.Blobs) != 2 {
+		t.Fatalf("expected 2 unique blobs for 3 files (2 duplicates), got %d", len(m.Blobs))
+	}
+
+	seenURLs := make(map[string]string)
+	for _, f := range m.Files {
+		blob, ok := m.Blobs[f.SHA256]
+		if !ok {
+			t.Fatalf("file %s has no matching blob entry", f.Path)
+		}
+		if blob.Size != f.Size {
+			t.Errorf("blob size %d does not match file size %d", blob.Size, f.Size)
+		}
+		if f.URL != "https://example.com/sha256/"+f.SHA256[:2]+"/"+f.SHA256 {
+			t.Errorf("unexpected content-addressed URL: %s", f.URL)
+		}
+		seenURLs[f.Path] = f.URL
+	}
+
+	if seenURLs["a.txt"] != seenURLs["b.txt"] {
+		t.Error("files with identical content should resolve to the same blob URL")
+	}
+	if seenURLs["a.txt"] == seenURLs["c.txt"] {
+		t.Error("files with different content should not resolve to the same blob URL")
+	}
+}
+
+func TestLoad_RejectsMissingBlobReference(t *testing.T) {
+	tmpDir := t.TempDir()
+	manifestPath := filepath.Join(tmpDir, "manifest.json")
+
+	m := &Manifest{
+		ArtifactID: "test",
+		Version:    "v1",
+		Files: []File{
+			{Path: "a.txt", URL: "https://example.com/sha256/de/deadbeef", SHA256: "deadbeef"},
+		},
+		Blobs: map[string]BlobInfo{
+			"cafebabe": {Size: 4},
+		},
+	}
+
+	data, err := Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if err := os.WriteFile(manifestPath, data, 0644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	if _, err := Load(manifestPath); err == nil {
+		t.Error("expected Load to reject a file referencing a missing blob, got nil error")
+	}
+}
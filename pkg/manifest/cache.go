@@ -0,0 +1,101 @@
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultCacheFileName is the sidecar Generate consults when a caller
+// enables caching without naming a specific file.
+const DefaultCacheFileName = ".mlartifactfs-cache.json"
+
+// CacheEntry is one cached hash result.
+type CacheEntry struct {
+	SHA256     string `json:"sha256"`
+	ComputedAt int64  `json:"computed_at"` // unix seconds
+}
+
+// Cache is an on-disk hash cache keyed by (absPath, size, mtimeNs, inode),
+// so Generate can skip rehashing files that haven't changed on multi-TB
+// model repos. It's safe for concurrent use by Generate's hashing workers.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]CacheEntry
+}
+
+// LoadCache reads a cache sidecar from path. A missing file isn't an error;
+// it yields an empty cache ready to be populated and saved.
+func LoadCache(path string) (*Cache, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Cache{entries: make(map[string]CacheEntry)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cache: reading %s: %w", path, err)
+	}
+
+	entries := make(map[string]CacheEntry)
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("cache: parsing %s: %w", path, err)
+	}
+	return &Cache{entries: entries}, nil
+}
+
+// Save writes the cache to path as JSON.
+func (c *Cache) Save(path string) error {
+	c.mu.Lock()
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("cache: writing %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Cache) get(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	return e, ok
+}
+
+func (c *Cache) put(key string, entry CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]CacheEntry)
+	}
+	c.entries[key] = entry
+}
+
+// cacheKey builds the lookup key for a file from its absolute path and stat
+// info. Comparing size, mtime, and inode together catches the common cases
+// (content changed, file replaced, file moved onto the same path) that any
+// single one of them would miss.
+func cacheKey(absPath string, info os.FileInfo) string {
+	return fmt.Sprintf("%s|%d|%d|%d", absPath, info.Size(), info.ModTime().UnixNano(), fileInode(info))
+}
+
+// fileInode extracts the inode number from a FileInfo on platforms that
+// expose it via syscall.Stat_t (Linux, macOS — the platforms the FUSE mount
+// command targets). It returns 0 where that's unavailable, which still
+// leaves path+size+mtime to distinguish files.
+func fileInode(info os.FileInfo) uint64 {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0
+	}
+	return uint64(stat.Ino)
+}
+
+func nowUnix() int64 {
+	return time.Now().Unix()
+}
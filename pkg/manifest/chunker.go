@@ -0,0 +1,168 @@
+package manifest
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+)
+
+// Size constants used as sensible defaults for ChunkConfig.
+const (
+	MiB = 1 << 20
+
+	defaultMinChunkSize    = 2 * MiB
+	defaultTargetChunkSize = 8 * MiB
+	defaultMaxChunkSize    = 16 * MiB
+
+	// rollingWindowSize is the width of the rolling-hash window used to
+	// decide chunk cut points. 64 bytes is enough context to smooth out
+	// local repetition without being so wide that boundaries drift when a
+	// byte range shifts (the classic CDC insertion/deletion problem).
+	rollingWindowSize = 64
+
+	// rollingBase is the multiplier for the polynomial rolling hash. Using
+	// unsigned 64-bit arithmetic lets the hash wrap instead of overflow.
+	rollingBase = uint64(257)
+)
+
+// ChunkConfig bounds content-defined chunking. MinSize/TargetSize/MaxSize
+// mirror FastCDC's normalized chunking: a stricter cut mask is used before
+// TargetSize is reached and a laxer one after, which keeps the resulting
+// chunk-size distribution tight around TargetSize instead of the long tail
+// a single fixed mask produces.
+type ChunkConfig struct {
+	Enabled    bool
+	MinSize    int64
+	TargetSize int64
+	MaxSize    int64
+}
+
+// DefaultChunkConfig returns the chunk bounds used when a caller enables
+// chunking without specifying its own sizes.
+func DefaultChunkConfig() ChunkConfig {
+	return ChunkConfig{
+		Enabled:    true,
+		MinSize:    defaultMinChunkSize,
+		TargetSize: defaultTargetChunkSize,
+		MaxSize:    defaultMaxChunkSize,
+	}
+}
+
+// chunkFile splits the file at path into content-defined chunks according to
+// cfg. Files at or below cfg.MinSize are returned as a single chunk spanning
+// the whole file. Chunk.URL is left empty; callers fill it in once they know
+// the file's URL.
+func chunkFile(path string, cfg ChunkConfig) ([]Chunk, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Size() <= cfg.MinSize {
+		hasher := sha256.New()
+		if _, err := bufio.NewReader(f).WriteTo(hasher); err != nil {
+			return nil, err
+		}
+		return []Chunk{{Offset: 0, Size: info.Size(), SHA256: hex.EncodeToString(hasher.Sum(nil))}}, nil
+	}
+
+	maskBits := bitLength(cfg.TargetSize)
+	// Fewer required zero bits before TargetSize (harder to hit) keeps
+	// chunks growing toward the target; more required zero bits after
+	// (easier to hit) forces a cut soon once the target is passed.
+	maskSmall := uint64(1)<<(maskBits+2) - 1
+	maskLarge := uint64(1)<<(maskBits-2) - 1
+
+	var (
+		chunks       []Chunk
+		window       [rollingWindowSize]byte
+		windowPos    int
+		windowFilled int
+		rollHash     uint64
+		// highPow == rollingBase^(rollingWindowSize-1), used to remove the
+		// byte falling out of the window when it slides forward.
+		highPow = uint64(1)
+	)
+	for i := 0; i < rollingWindowSize-1; i++ {
+		highPow *= rollingBase
+	}
+
+	reader := bufio.NewReaderSize(f, 1<<20)
+	chunkStart := int64(0)
+	chunkHasher := sha256.New()
+	var pos int64
+
+	flush := func(end int64) {
+		chunks = append(chunks, Chunk{
+			Offset: chunkStart,
+			Size:   end - chunkStart,
+			SHA256: hex.EncodeToString(chunkHasher.Sum(nil)),
+		})
+		chunkStart = end
+		chunkHasher = sha256.New()
+	}
+
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			break
+		}
+
+		chunkHasher.Write([]byte{b})
+
+		outgoing := window[windowPos]
+		if windowFilled == rollingWindowSize {
+			rollHash -= uint64(outgoing) * highPow
+		} else {
+			windowFilled++
+		}
+		rollHash = rollHash*rollingBase + uint64(b)
+		window[windowPos] = b
+		windowPos = (windowPos + 1) % rollingWindowSize
+
+		pos++
+		chunkSize := pos - chunkStart
+
+		if chunkSize >= cfg.MaxSize {
+			flush(pos)
+			continue
+		}
+
+		if chunkSize < cfg.MinSize || windowFilled < rollingWindowSize {
+			continue
+		}
+
+		mask := maskSmall
+		if chunkSize >= cfg.TargetSize {
+			mask = maskLarge
+		}
+		if rollHash&mask == 0 {
+			flush(pos)
+		}
+	}
+
+	if pos > chunkStart {
+		flush(pos)
+	}
+
+	return chunks, nil
+}
+
+// bitLength returns the position of the highest set bit in v, i.e. the
+// number of bits needed to represent it. Used to scale the CDC cut masks to
+// the configured target chunk size.
+func bitLength(v int64) uint {
+	var n uint
+	for v > 0 {
+		v >>= 1
+		n++
+	}
+	return n
+}
@@ -0,0 +1,160 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeRandomFile(t *testing.T, path string, size int, seed int64) []byte {
+	t.Helper()
+	data := make([]byte, size)
+	rand.New(rand.NewSource(seed)).Read(data)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	return data
+}
+
+func TestChunkFile_SmallFileIsSingleChunk(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "small.bin")
+	data := writeRandomFile(t, path, 1024, 1)
+
+	cfg := DefaultChunkConfig()
+	chunks, err := chunkFile(path, cfg)
+	if err != nil {
+		t.Fatalf("chunkFile failed: %v", err)
+	}
+	if len(chunks) != 1 {
+		t.Fatalf("expected 1 chunk for a file below MinSize, got %d", len(chunks))
+	}
+	if chunks[0].Offset != 0 || chunks[0].Size != int64(len(data)) {
+		t.Errorf("expected chunk spanning whole file, got offset=%d size=%d", chunks[0].Offset, chunks[0].Size)
+	}
+
+	sum := sha256.Sum256(data)
+	if chunks[0].SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("chunk SHA256 mismatch")
+	}
+}
+
+func TestChunkFile_RespectsBounds(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "large.bin")
+	data := writeRandomFile(t, path, 4*MiB, 2)
+
+	cfg := ChunkConfig{Enabled: true, MinSize: 64 * 1024, TargetSize: 256 * 1024, MaxSize: 512 * 1024}
+	chunks, err := chunkFile(path, cfg)
+	if err != nil {
+		t.Fatalf("chunkFile failed: %v", err)
+	}
+	if len(chunks) < 2 {
+		t.Fatalf("expected a 4MiB file to split into multiple chunks, got %d", len(chunks))
+	}
+
+	var total int64
+	for i, c := range chunks {
+		if c.Size > cfg.MaxSize {
+			t.Errorf("chunk %d exceeds MaxSize: %d > %d", i, c.Size, cfg.MaxSize)
+		}
+		if i < len(chunks)-1 && c.Size < cfg.MinSize {
+			t.Errorf("non-final chunk %d below MinSize: %d < %d", i, c.Size, cfg.MinSize)
+		}
+		total += c.Size
+	}
+	if total != int64(len(data)) {
+		t.Errorf("chunk sizes sum to %d, want %d", total, len(data))
+	}
+}
+
+func TestChunkFile_DeterministicAcrossRuns(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "repeat.bin")
+	writeRandomFile(t, path, 2*MiB, 3)
+
+	cfg := ChunkConfig{Enabled: true, MinSize: 32 * 1024, TargetSize: 128 * 1024, MaxSize: 256 * 1024}
+
+	first, err := chunkFile(path, cfg)
+	if err != nil {
+		t.Fatalf("chunkFile failed: %v", err)
+	}
+	second, err := chunkFile(path, cfg)
+	if err != nil {
+		t.Fatalf("chunkFile failed: %v", err)
+	}
+
+	if len(first) != len(second) {
+		t.Fatalf("chunk count differs across runs: %d vs %d", len(first), len(second))
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("chunk %d differs across runs: %+v vs %+v", i, first[i], second[i])
+		}
+	}
+}
+
+func TestChunkFile_InsertionShiftsOnlyNeighboringChunks(t *testing.T) {
+	tmpDir := t.TempDir()
+	base := writeRandomFile(t, filepath.Join(tmpDir, "base.bin"), 3*MiB, 4)
+
+	cfg := ChunkConfig{Enabled: true, MinSize: 32 * 1024, TargetSize: 128 * 1024, MaxSize: 256 * 1024}
+	baseChunks, err := chunkFile(filepath.Join(tmpDir, "base.bin"), cfg)
+	if err != nil {
+		t.Fatalf("chunkFile failed: %v", err)
+	}
+
+	// Insert a few bytes in the middle of the file and verify that most
+	// chunk hashes on either side of the insertion point are unaffected,
+	// which is the whole point of content-defined (as opposed to
+	// fixed-size) chunking.
+	mid := len(base) / 2
+	modified := append([]byte{}, base[:mid]...)
+	modified = append(modified, []byte("EXTRA BYTES INSERTED HERE")...)
+	modified = append(modified, base[mid:]...)
+
+	modPath := filepath.Join(tmpDir, "modified.bin")
+	if err := os.WriteFile(modPath, modified, 0644); err != nil {
+		t.Fatalf("failed to write modified file: %v", err)
+	}
+	modChunks, err := chunkFile(modPath, cfg)
+	if err != nil {
+		t.Fatalf("chunkFile failed: %v", err)
+	}
+
+	baseHashes := make(map[string]bool, len(baseChunks))
+	for _, c := range baseChunks {
+		baseHashes[c.SHA256] = true
+	}
+	shared := 0
+	for _, c := range modChunks {
+		if baseHashes[c.SHA256] {
+			shared++
+		}
+	}
+	if shared == 0 {
+		t.Error("expected at least some chunks to be shared between base and modified files")
+	}
+}
+
+func TestChunkFile_MatchesStandaloneHash(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "verify.bin")
+	data := writeRandomFile(t, path, 1*MiB, 5)
+
+	cfg := ChunkConfig{Enabled: true, MinSize: 16 * 1024, TargetSize: 64 * 1024, MaxSize: 128 * 1024}
+	chunks, err := chunkFile(path, cfg)
+	if err != nil {
+		t.Fatalf("chunkFile failed: %v", err)
+	}
+
+	for _, c := range chunks {
+		want := sha256.Sum256(data[c.Offset : c.Offset+c.Size])
+		if c.SHA256 != hex.EncodeToString(want[:]) {
+			t.Errorf("chunk at offset %d has wrong SHA256", c.Offset)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package manifest
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+)
+
+// Compression codec names used in File.Compression.
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+	CompressionAuto = "auto"
+)
+
+// compressedSiblingSuffixes lists every suffix compressFile may append to a
+// scanned file's path to write its compressed sibling (e.g. "data.bin" ->
+// "data.bin.gz"). The walk in Generate skips paths ending in one of these so
+// a sibling written by one Generate run isn't indexed as its own File on the
+// next — see compressWithGzip.
+var compressedSiblingSuffixes = []string{".gz"}
+
+// hasCompressedSiblingSuffix reports whether name ends in a suffix Generate
+// itself appends for a compressed sibling (see compressedSiblingSuffixes).
+func hasCompressedSiblingSuffix(name string) bool {
+	for _, suffix := range compressedSiblingSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// autoSampleSize is how much of a file "auto" mode reads to estimate
+// compressibility before deciding whether to compress the whole thing.
+const autoSampleSize = 1 << 20 // 1 MiB
+
+// autoSkipRatio is the estimated compressed/uncompressed ratio above which
+// "auto" mode leaves a file uncompressed. Already-compressed formats (e.g.
+// safetensors shards, quantized GGUF blobs) rarely beat ~0.97 and aren't
+// worth the CPU time to re-compress.
+const autoSkipRatio = 0.97
+
+// CompressionConfig selects how Generate compresses files. The zero value
+// ("" mode) behaves like CompressionNone.
+type CompressionConfig struct {
+	Mode string
+}
+
+// compressFile compresses the file at path using mode, writing the result
+// to a sibling file (path+".gz" for gzip) and returning the codec actually
+// used along with the compressed size and SHA256. "auto" resolves to either
+// gzip or none depending on estimateCompressionRatio. "none" and "" are
+// no-ops and return CompressionNone with zero size/hash.
+func compressFile(path string, mode string) (codec string, compressedSize int64, compressedSHA256 string, err error) {
+	switch mode {
+	case "", CompressionNone:
+		return CompressionNone, 0, "", nil
+
+	case CompressionAuto:
+		ratio, err := estimateCompressionRatio(path)
+		if err != nil {
+			return "", 0, "", err
+		}
+		if ratio > autoSkipRatio {
+			return CompressionNone, 0, "", nil
+		}
+		return compressFile(path, CompressionGzip)
+
+	case CompressionGzip:
+		return compressWithGzip(path)
+
+	case CompressionZstd:
+		// zstd gives better ratios and speed than gzip, but this repo has
+		// no external dependencies today and the stdlib doesn't implement
+		// it. Fail loudly rather than silently falling back to a different
+		// codec than the caller asked for.
+		return "", 0, "", fmt.Errorf("compression: zstd is not yet supported (no vendored encoder); use --compression=gzip or auto")
+
+	default:
+		return "", 0, "", fmt.Errorf("compression: unknown mode %q", mode)
+	}
+}
+
+func compressWithGzip(path string) (codec string, compressedSize int64, compressedSHA256 string, err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", 0, "", err
+	}
+	defer src.Close()
+
+	dstPath := path + ".gz"
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return "", 0, "", err
+	}
+	defer dst.Close()
+
+	hasher := sha256.New()
+	counter := &countingWriter{}
+	mw := io.MultiWriter(dst, hasher, counter)
+
+	gz := gzip.NewWriter(mw)
+	if _, err := io.Copy(gz, bufio.NewReader(src)); err != nil {
+		return "", 0, "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", 0, "", err
+	}
+
+	return CompressionGzip, counter.n, hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+type countingWriter struct{ n int64 }
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
+
+// estimateCompressionRatio samples the first autoSampleSize bytes of path
+// and estimates how compressible the file is via Shannon entropy: data close
+// to 8 bits of entropy per byte (e.g. already-compressed or encrypted data)
+// yields an estimated ratio near 1.0, while low-entropy data (text, padded
+// tensors) yields a lower estimate.
+func estimateCompressionRatio(path string) (float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, autoSampleSize)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return 0, err
+	}
+	buf = buf[:n]
+	if n == 0 {
+		return 0, nil
+	}
+
+	var counts [256]int
+	for _, b := range buf {
+		counts[b]++
+	}
+
+	var entropy float64
+	total := float64(n)
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / total
+		entropy -= p * math.Log2(p)
+	}
+
+	// entropy is bits-per-byte in [0, 8]; normalize to a ratio in [0, 1].
+	return entropy / 8, nil
+}
@@ -0,0 +1,145 @@
+package manifest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompressFile_Gzip(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "data.txt")
+	content := bytes.Repeat([]byte("the quick brown fox jumps over the lazy dog\n"), 1000)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	codec, compressedSize, compressedHash, err := compressFile(path, CompressionGzip)
+	if err != nil {
+		t.Fatalf("compressFile failed: %v", err)
+	}
+	if codec != CompressionGzip {
+		t.Errorf("expected codec %q, got %q", CompressionGzip, codec)
+	}
+	if compressedSize >= int64(len(content)) {
+		t.Errorf("expected highly repetitive content to shrink, got compressed size %d >= original %d", compressedSize, len(content))
+	}
+
+	gzPath := path + ".gz"
+	data, err := os.ReadFile(gzPath)
+	if err != nil {
+		t.Fatalf("expected sibling .gz file to exist: %v", err)
+	}
+	if int64(len(data)) != compressedSize {
+		t.Errorf("reported compressed size %d does not match sibling file size %d", compressedSize, len(data))
+	}
+
+	sum := sha256.Sum256(data)
+	if compressedHash != hex.EncodeToString(sum[:]) {
+		t.Error("reported compressed SHA256 does not match sibling file contents")
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("sibling file is not valid gzip: %v", err)
+	}
+	decompressed, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("failed to decompress sibling file: %v", err)
+	}
+	if !bytes.Equal(decompressed, content) {
+		t.Error("decompressed content does not match original")
+	}
+}
+
+func TestCompressFile_NoneIsNoop(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "data.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	codec, size, hash, err := compressFile(path, CompressionNone)
+	if err != nil {
+		t.Fatalf("compressFile failed: %v", err)
+	}
+	if codec != CompressionNone || size != 0 || hash != "" {
+		t.Errorf("expected no-op result, got codec=%q size=%d hash=%q", codec, size, hash)
+	}
+	if _, err := os.Stat(path + ".gz"); err == nil {
+		t.Error("expected no sibling file to be created for CompressionNone")
+	}
+}
+
+func TestCompressFile_ZstdNotImplemented(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "data.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	if _, _, _, err := compressFile(path, CompressionZstd); err == nil {
+		t.Error("expected an error for the unimplemented zstd codec, got nil")
+	}
+}
+
+func TestEstimateCompressionRatio_TextVsRandom(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	textPath := filepath.Join(tmpDir, "text.txt")
+	text := bytes.Repeat([]byte("aaaaaaaaaa"), 10000)
+	if err := os.WriteFile(textPath, text, 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	randomPath := filepath.Join(tmpDir, "random.bin")
+	random := make([]byte, 100000)
+	for i := range random {
+		random[i] = byte(i*2654435761 + 17)
+	}
+	if err := os.WriteFile(randomPath, random, 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	textRatio, err := estimateCompressionRatio(textPath)
+	if err != nil {
+		t.Fatalf("estimateCompressionRatio failed: %v", err)
+	}
+	randomRatio, err := estimateCompressionRatio(randomPath)
+	if err != nil {
+		t.Fatalf("estimateCompressionRatio failed: %v", err)
+	}
+
+	if textRatio >= randomRatio {
+		t.Errorf("expected highly repetitive text to estimate a lower ratio than pseudo-random bytes, got text=%f random=%f", textRatio, randomRatio)
+	}
+}
+
+func TestGenerateWithOptions_AutoCompressionSkipsHighEntropyFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	random := make([]byte, 2*autoSampleSize)
+	for i := range random {
+		random[i] = byte(i*2654435761 + 17)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "weights.safetensors"), random, 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	m, err := GenerateWithOptions(tmpDir, "test", "v1", "https://example.com", nil, GenerateOptions{Compression: CompressionConfig{Mode: CompressionAuto}})
+	if err != nil {
+		t.Fatalf("GenerateWithOptions failed: %v", err)
+	}
+
+	if len(m.Files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(m.Files))
+	}
+	if m.Files[0].Compression != CompressionNone {
+		t.Errorf("expected auto mode to skip compressing high-entropy content, got %q", m.Files[0].Compression)
+	}
+}
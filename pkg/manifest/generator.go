@@ -4,9 +4,58 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 )
 
+// GenerateOptions controls optional Generate behavior that most callers can
+// leave at the zero value. New knobs (compression, dedup, signing, ...)
+// should be added here rather than growing Generate's positional parameter
+// list further.
+type GenerateOptions struct {
+	// Chunking enables content-defined chunking of files larger than
+	// Chunking.MinSize. The zero value leaves chunking disabled.
+	Chunking ChunkConfig
+
+	// ContentAddressed switches File.URL from the logical path layout
+	// (urlPrefix + relPath) to a content-addressed layout keyed by SHA256
+	// (urlPrefix + "/sha256/" + hash[:2] + "/" + hash), and populates
+	// Manifest.Blobs with one entry per unique hash. Files that happen to
+	// share a hash (e.g. identical weights across versions) then resolve
+	// to the same blob and are only uploaded/downloaded once.
+	ContentAddressed bool
+
+	// Compression controls whether Generate writes a compressed sibling
+	// copy of each file and records its codec/size/hash. The zero value
+	// leaves every file uncompressed.
+	Compression CompressionConfig
+
+	// Cache, if non-nil, is consulted (and updated) for each file's SHA256
+	// instead of always rehashing, keyed by path/size/mtime/inode. This is
+	// what turns re-running Generate on a multi-TB model repo from a
+	// >30-minute rehash into a near-instant no-op for unchanged files.
+	Cache *Cache
+
+	// ForceRehash ignores Cache for reading (a fresh hash is always
+	// computed) while still writing results back into it, for a --verify
+	// pass that wants to detect bit rot rather than trust stale entries.
+	ForceRehash bool
+
+	// Jobs bounds how many files are hashed/chunked/compressed
+	// concurrently. Zero means runtime.NumCPU().
+	Jobs int
+
+	// PrefetchRules expands glob patterns against the walked file set into
+	// Manifest.Prefetch (for older clients) while also being preserved as
+	// Manifest.PrefetchRules (for clients that understand priority tiers
+	// and partial warming). Matched paths are merged with any literal
+	// prefetchPaths passed to Generate, deduplicated, highest Priority
+	// first.
+	PrefetchRules []PrefetchRule
+}
+
 // Generate creates a manifest by walking a directory tree and computing
 // metadata for each file (size, SHA256 hash, URL).
 //
@@ -14,11 +63,30 @@ import (
 //   - dir: Local directory to scan
 //   - id: Artifact identifier (e.g., "llama-7b")
 //   - version: Version string (e.g., "v1.0")
-//   - urlPrefix: Base URL for S3 storage (e.g., "https://bucket.s3.amazonaws.com/models/v1")
+//   - urlPrefix: Base URL for storage, e.g. "https://bucket.s3.amazonaws.com/models/v1",
+//     "gs://bucket/models/v1", "azure://account/container/models/v1", "oci://registry/repo",
+//     or "file:///mnt/artifacts/v1" — see ResolveBackend for the full set of schemes.
 //   - prefetchPaths: List of file paths to prefetch at mount time
 //
 // Returns a Manifest struct or an error if the directory cannot be read.
 func Generate(dir string, id string, version string, urlPrefix string, prefetchPaths []string) (*Manifest, error) {
+	return GenerateWithOptions(dir, id, version, urlPrefix, prefetchPaths, GenerateOptions{})
+}
+
+// GenerateWithOptions is Generate with additional opt-in behavior. See
+// GenerateOptions for what can be configured.
+func GenerateWithOptions(dir string, id string, version string, urlPrefix string, prefetchPaths []string, opts GenerateOptions) (*Manifest, error) {
+	backend, err := ResolveBackend(urlPrefix)
+	if err != nil {
+		return nil, err
+	}
+	return GenerateWithBackend(dir, id, version, backend, prefetchPaths, opts)
+}
+
+// GenerateWithBackend is GenerateWithOptions for callers that already have a
+// Backend (e.g. an S3 backend constructed with NewS3Backend for path-style
+// URLs, which ResolveBackend's single-string convention can't express).
+func GenerateWithBackend(dir string, id string, version string, backend Backend, prefetchPaths []string, opts GenerateOptions) (*Manifest, error) {
 	// Validate inputs
 	if id == "" || version == "" {
 		return nil, fmt.Errorf("id and version are required")
@@ -33,14 +101,6 @@ func Generate(dir string, id string, version string, urlPrefix string, prefetchP
 		return nil, fmt.Errorf("path is not a directory: %s", dir)
 	}
 
-	// Validate URL prefix (basic check)
-	if !strings.HasPrefix(urlPrefix, "http://") && !strings.HasPrefix(urlPrefix, "https://") {
-		return nil, fmt.Errorf("url-prefix must start with http:// or https://")
-	}
-
-	// Strip trailing slash from URL prefix
-	urlPrefix = strings.TrimSuffix(urlPrefix, "/")
-
 	// Initialize manifest
 	manifest := &Manifest{
 		ArtifactID: id,
@@ -50,7 +110,10 @@ func Generate(dir string, id string, version string, urlPrefix string, prefetchP
 		Files:      []File{},
 	}
 
-	// Walk directory tree
+	// Walk directory tree to build the (cheap, serial, I/O-light) list of
+	// files to process; hashing/chunking/compression happen afterward so
+	// they can run concurrently without racing the walk itself.
+	var entries []fileEntry
 	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -66,6 +129,15 @@ func Generate(dir string, id string, version string, urlPrefix string, prefetchP
 			return nil
 		}
 
+		// Skip compressed siblings a previous Generate run wrote next to
+		// their source file (e.g. "data.bin.gz"), so re-running Generate
+		// with compression enabled stays idempotent instead of indexing
+		// the sibling as its own File and re-compressing it on the next
+		// pass.
+		if hasCompressedSiblingSuffix(info.Name()) {
+			return nil
+		}
+
 		// Skip symlinks (for MVP simplicity)
 		if info.Mode()&os.ModeSymlink != 0 {
 			return nil
@@ -80,23 +152,7 @@ func Generate(dir string, id string, version string, urlPrefix string, prefetchP
 		// Normalize path to Unix-style (forward slashes)
 		relPath = filepath.ToSlash(relPath)
 
-		// Compute SHA256 hash
-		hash, err := hashFile(path)
-		if err != nil {
-			return fmt.Errorf("failed to hash file %s: %w", relPath, err)
-		}
-
-		// Construct S3 URL
-		fileURL := urlPrefix + "/" + relPath
-
-		// Add file to manifest
-		manifest.Files = append(manifest.Files, File{
-			Path:        relPath,
-			URL:         fileURL,
-			Size:        info.Size(),
-			SHA256:      hash,
-			Compression: "none", // MVP: no compression support
-		})
+		entries = append(entries, fileEntry{path: path, relPath: relPath, info: info})
 
 		return nil
 	})
@@ -105,9 +161,223 @@ func Generate(dir string, id string, version string, urlPrefix string, prefetchP
 		return nil, fmt.Errorf("failed to walk directory: %w", err)
 	}
 
+	files, err := hashEntries(entries, backend, opts)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Files = files
+
+	if len(opts.PrefetchRules) > 0 {
+		manifest.PrefetchRules = opts.PrefetchRules
+		manifest.Prefetch = mergeUniqueStrings(manifest.Prefetch, expandPrefetchRules(manifest.Files, opts.PrefetchRules))
+	}
+
+	if opts.ContentAddressed {
+		manifest.Blobs = buildBlobIndex(manifest.Files)
+	}
+
+	root, proofs, err := ComputeMerkleRoot(manifest.Files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute merkle root: %w", err)
+	}
+	manifest.MerkleRoot = root
+	for i := range manifest.Files {
+		manifest.Files[i].MerkleProof = proofs[manifest.Files[i].SHA256]
+	}
+
 	return manifest, nil
 }
 
+// fileEntry is one file discovered by the directory walk, carrying what the
+// hashing stage needs without re-touching the filesystem to get it.
+type fileEntry struct {
+	path    string
+	relPath string
+	info    os.FileInfo
+}
+
+// hashEntries computes the SHA256 (and, per opts, compression/chunking) for
+// each entry using a worker pool, preserving the walk's original order in
+// the returned slice. A single error from any worker aborts the whole batch.
+func hashEntries(entries []fileEntry, backend Backend, opts GenerateOptions) ([]File, error) {
+	files := make([]File, len(entries))
+
+	jobs := opts.Jobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+
+	sem := make(chan struct{}, jobs)
+	errCh := make(chan error, 1)
+	var wg sync.WaitGroup
+
+	for i, entry := range entries {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, entry fileEntry) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			file, err := buildFile(entry, backend, opts)
+			if err != nil {
+				select {
+				case errCh <- err:
+				default:
+				}
+				return
+			}
+			files[i] = file
+		}(i, entry)
+	}
+	wg.Wait()
+
+	select {
+	case err := <-errCh:
+		return nil, err
+	default:
+	}
+
+	return files, nil
+}
+
+// buildFile computes one file's manifest entry: hash (via cache when
+// configured), object URL, and optional compression/chunking metadata.
+func buildFile(entry fileEntry, backend Backend, opts GenerateOptions) (File, error) {
+	hash, err := resolveHash(entry, opts)
+	if err != nil {
+		return File{}, fmt.Errorf("failed to hash file %s: %w", entry.relPath, err)
+	}
+
+	// Resolve the object URL through the backend: logical path by default,
+	// content-addressed (relPath == "") when enabled.
+	var fileURL string
+	if opts.ContentAddressed {
+		fileURL = backend.ObjectURL("", hash)
+	} else {
+		fileURL = backend.ObjectURL(entry.relPath, hash)
+	}
+
+	file := File{
+		Path:        entry.relPath,
+		URL:         fileURL,
+		Size:        entry.info.Size(),
+		SHA256:      hash,
+		Compression: CompressionNone,
+	}
+
+	if opts.Compression.Mode != "" && opts.Compression.Mode != CompressionNone {
+		codec, compressedSize, compressedHash, err := compressFile(entry.path, opts.Compression.Mode)
+		if err != nil {
+			return File{}, fmt.Errorf("failed to compress file %s: %w", entry.relPath, err)
+		}
+		file.Compression = codec
+		if codec != CompressionNone {
+			file.CompressedSize = compressedSize
+			file.CompressedSHA256 = compressedHash
+		}
+	}
+
+	if opts.Chunking.Enabled && entry.info.Size() > opts.Chunking.MinSize {
+		chunks, err := chunkFile(entry.path, opts.Chunking)
+		if err != nil {
+			return File{}, fmt.Errorf("failed to chunk file %s: %w", entry.relPath, err)
+		}
+		for i := range chunks {
+			chunks[i].URL = fmt.Sprintf("%s#bytes=%d-%d", fileURL, chunks[i].Offset, chunks[i].Offset+chunks[i].Size-1)
+			chunks[i].Compression = file.Compression
+		}
+		file.Chunks = chunks
+	}
+
+	return file, nil
+}
+
+// resolveHash returns entry's SHA256, consulting and updating opts.Cache
+// when one is configured. ForceRehash skips the read but still refreshes
+// the cache, for a --verify pass that wants to catch bit rot rather than
+// trust a stale entry.
+func resolveHash(entry fileEntry, opts GenerateOptions) (string, error) {
+	if opts.Cache == nil {
+		return hashFile(entry.path)
+	}
+
+	absPath, err := filepath.Abs(entry.path)
+	if err != nil {
+		return "", err
+	}
+	key := cacheKey(absPath, entry.info)
+
+	if !opts.ForceRehash {
+		if cached, ok := opts.Cache.get(key); ok {
+			return cached.SHA256, nil
+		}
+	}
+
+	hash, err := hashFile(entry.path)
+	if err != nil {
+		return "", err
+	}
+	opts.Cache.put(key, CacheEntry{SHA256: hash, ComputedAt: nowUnix()})
+	return hash, nil
+}
+
+// buildBlobIndex groups files by SHA256 and emits one BlobInfo per unique
+// hash, so identical file content is only described (and uploaded) once
+// regardless of how many logical paths reference it.
+func buildBlobIndex(files []File) map[string]BlobInfo {
+	blobs := make(map[string]BlobInfo)
+	for _, f := range files {
+		if _, ok := blobs[f.SHA256]; ok {
+			continue
+		}
+		blobs[f.SHA256] = BlobInfo{
+			Size:        f.Size,
+			Compression: f.Compression,
+		}
+	}
+	return blobs
+}
+
+// expandPrefetchRules matches each rule's Pattern against every file's Path,
+// returning the matched paths ordered by rule Priority (highest first, ties
+// broken by rule order) and deduplicated. This is the "ResolvedPrefetch"
+// Generate writes into Manifest.Prefetch for clients that don't understand
+// PrefetchRules.
+func expandPrefetchRules(files []File, rules []PrefetchRule) []string {
+	sorted := append([]PrefetchRule(nil), rules...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Priority > sorted[j].Priority })
+
+	seen := make(map[string]bool)
+	var resolved []string
+	for _, rule := range sorted {
+		for _, f := range files {
+			if seen[f.Path] || !matchGlob(rule.Pattern, f.Path) {
+				continue
+			}
+			seen[f.Path] = true
+			resolved = append(resolved, f.Path)
+		}
+	}
+	return resolved
+}
+
+// mergeUniqueStrings concatenates a and b, dropping duplicates while
+// preserving first-seen order.
+func mergeUniqueStrings(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	merged := make([]string, 0, len(a)+len(b))
+	for _, list := range [][]string{a, b} {
+		for _, s := range list {
+			if seen[s] {
+				continue
+			}
+			seen[s] = true
+			merged = append(merged, s)
+		}
+	}
+	return merged
+}
+
 // normalizePrefetchPaths processes the prefetch paths list:
 // - Trims whitespace
 // - Converts to forward slashes
@@ -235,10 +235,10 @@ func TestGenerate_InvalidInputs(t *testing.T) {
 			wantErr:   true,
 		},
 		{
-			name:      "Invalid URL prefix (no http/https)",
+			name:      "Invalid URL prefix (unsupported scheme)",
 			id:        "test",
 			version:   "v1",
-			urlPrefix: "s3://bucket/path",
+			urlPrefix: "ftp://bucket/path",
 			wantErr:   true,
 		},
 		{
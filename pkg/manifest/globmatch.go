@@ -0,0 +1,41 @@
+package manifest
+
+import (
+	"path"
+	"strings"
+)
+
+// matchGlob reports whether name (a "/"-separated relative path) matches a
+// doublestar-style glob pattern: "**" matches zero or more whole path
+// segments, while each remaining segment is matched with path.Match's usual
+// single-segment wildcards ("*", "?", "[...]"). This is a small,
+// dependency-free subset of https://github.com/bmatcuk/doublestar — this
+// repo has no external dependencies today and prefetch patterns don't need
+// doublestar's full feature set.
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+
+	if pattern[0] == "**" {
+		if matchSegments(pattern[1:], name) {
+			return true
+		}
+		if len(name) == 0 {
+			return false
+		}
+		return matchSegments(pattern, name[1:])
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], name[1:])
+}
@@ -0,0 +1,31 @@
+package manifest
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"config.json", "config.json", true},
+		{"config.json", "sub/config.json", false},
+		{"tokenizer*", "tokenizer.json", true},
+		{"tokenizer*", "tokenizer_config.json", true},
+		{"tokenizer*", "sub/tokenizer.json", false},
+		{"**/*.safetensors.index.json", "model.safetensors.index.json", true},
+		{"**/*.safetensors.index.json", "shards/model.safetensors.index.json", true},
+		{"**/*.safetensors.index.json", "shards/nested/model.safetensors.index.json", true},
+		{"**/*.safetensors.index.json", "shards/model.bin", false},
+		{"**", "any/depth/at/all.txt", true},
+		{"**", "top.txt", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"_"+tt.name, func(t *testing.T) {
+			if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+				t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,26 +2,106 @@ package manifest
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 )
 
 // Manifest defines the structure of a manifest file that describes
 // a virtual filesystem for lazy-loading files from remote storage.
 type Manifest struct {
-	ArtifactID string   `json:"artifact_id"`
-	Version    string   `json:"version"`
-	MountPath  string   `json:"mount_path"`
-	Prefetch   []string `json:"prefetch"`
-	Files      []File   `json:"files"`
+	ArtifactID string `json:"artifact_id"`
+	Version    string `json:"version"`
+	MountPath  string `json:"mount_path"`
+
+	// Prefetch is the flat, resolved list of paths to warm at mount time.
+	// Older clients that don't understand PrefetchRules can keep reading
+	// just this field; Generate populates it from both the literal paths a
+	// caller passed in and any PrefetchRules expanded against the walked
+	// file set.
+	Prefetch []string `json:"prefetch"`
+
+	// PrefetchRules is the structured prefetch policy: glob patterns with a
+	// priority and a warm-bytes cap. Newer mount clients should prefer this
+	// over Prefetch when present, since it preserves ordering and partial
+	// (header-only) warming that the flattened list can't express.
+	PrefetchRules []PrefetchRule `json:"prefetch_rules,omitempty"`
+
+	Files []File              `json:"files"`
+	Blobs map[string]BlobInfo `json:"blobs,omitempty"`
+
+	// MerkleRoot commits to the full set of File.SHA256 leaves with a
+	// single 32-byte hash, computed by ComputeMerkleRoot. A signed manifest
+	// (see the sign subpackage) only needs to cover this one field for a
+	// mount client to be able to prove any single file's inclusion via its
+	// MerkleProof, without trusting the rest of the manifest body.
+	MerkleRoot string `json:"merkle_root,omitempty"`
 }
 
-// File represents a single file in the manifest with its metadata.
-type File struct {
-	Path        string `json:"path"`
-	URL         string `json:"url"`
+// BlobInfo records metadata for one unique content-addressed blob. It is
+// keyed by SHA256 in Manifest.Blobs so that files sharing identical content
+// (e.g. unchanged weights across fine-tuned versions of a model) reference
+// a single upload instead of duplicating it per path.
+type BlobInfo struct {
 	Size        int64  `json:"size"`
-	SHA256      string `json:"sha256"`
 	Compression string `json:"compression"`
+	// AltURL optionally points at a compressed alternative of the blob
+	// (e.g. a zstd-compressed copy) that a mount client may use instead.
+	AltURL string `json:"alt_url,omitempty"`
+}
+
+// File represents a single file in the manifest with its metadata.
+type File struct {
+	Path        string  `json:"path"`
+	URL         string  `json:"url"`
+	Size        int64   `json:"size"`
+	SHA256      string  `json:"sha256"`
+	Compression string  `json:"compression"`
+	Chunks      []Chunk `json:"chunks,omitempty"`
+
+	// CompressedSize and CompressedSHA256 describe the sibling compressed
+	// copy of the file (written alongside it as e.g. ".gz") when
+	// Compression is not "none". Size/SHA256 above always describe the
+	// original, uncompressed content so integrity checks don't depend on
+	// which codec was used.
+	CompressedSize   int64  `json:"compressed_size,omitempty"`
+	CompressedSHA256 string `json:"compressed_sha256,omitempty"`
+
+	// MerkleProof is the sibling hash chain that proves this file's SHA256
+	// is one of the leaves committed to by Manifest.MerkleRoot. See
+	// VerifyProof.
+	MerkleProof []string `json:"merkle_proof,omitempty"`
+}
+
+// PrefetchRule describes one prefetch policy entry: every File.Path
+// matching Pattern is queued for warming at mount time, in Priority order
+// (higher Priority first). MaxBytes, when set, caps warming to that many
+// leading bytes of each matched file (a header-only read) instead of
+// fetching it in full — useful for large sharded weight files where only
+// the tensor index actually needs to be warm.
+type PrefetchRule struct {
+	Pattern  string `json:"pattern"`
+	Priority int    `json:"priority"`
+	MaxBytes int64  `json:"max_bytes,omitempty"`
+}
+
+// Chunk describes one content-defined slice of a File. Chunks let the mount
+// command fetch a byte range instead of the whole file and let identical
+// ranges across file versions (e.g. unchanged tensor blocks in a fine-tune)
+// be recognized by their SHA256 without re-downloading them.
+type Chunk struct {
+	Offset int64  `json:"offset"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	URL    string `json:"url"`
+
+	// Compression mirrors the parent File's Compression codec today, since
+	// compressFile operates on the whole file and chunk boundaries are cut
+	// from the uncompressed content. It is carried per-chunk (rather than
+	// left for callers to look up on File) so a mount client can decide
+	// how to decode a single fetched byte range without re-reading the
+	// rest of the manifest entry. Once chunks are compressed independently
+	// (so a byte range is self-contained) this can differ per chunk.
+	Compression string `json:"compression,omitempty"`
 }
 
 // Marshal serializes a Manifest to pretty-printed JSON.
@@ -44,5 +124,13 @@ func Load(path string) (*Manifest, error) {
 		return nil, err
 	}
 
+	if len(m.Blobs) > 0 {
+		for _, f := range m.Files {
+			if _, ok := m.Blobs[f.SHA256]; !ok {
+				return nil, fmt.Errorf("manifest: file %q references blob %s which is not in the dedup index", f.Path, f.SHA256)
+			}
+		}
+	}
+
 	return &m, nil
 }
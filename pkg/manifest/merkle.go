@@ -0,0 +1,110 @@
+package manifest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+)
+
+// ComputeMerkleRoot builds a binary Merkle tree over the sorted, deduplicated
+// SHA256 hashes of files and returns its root plus an inclusion proof for
+// every unique hash. A level with an odd number of nodes duplicates its last
+// node so pairing stays even, the usual fix for unbalanced trees. Sibling
+// pairs are hashed in sorted order rather than left/right order so
+// VerifyProof doesn't need a direction bit alongside each proof entry.
+//
+// This lets a mount client that has only the signed MerkleRoot (see the
+// sign subpackage) verify a single fetched file against it via
+// File.MerkleProof, without needing or trusting the rest of the manifest.
+func ComputeMerkleRoot(files []File) (root string, proofs map[string][]string, err error) {
+	if len(files) == 0 {
+		return "", map[string][]string{}, nil
+	}
+
+	leaves := uniqueSortedHashes(files)
+
+	level := make([][]byte, len(leaves))
+	for i, h := range leaves {
+		b, err := hex.DecodeString(h)
+		if err != nil {
+			return "", nil, fmt.Errorf("merkle: invalid SHA256 %q: %w", h, err)
+		}
+		level[i] = b
+	}
+
+	proofs = make(map[string][]string, len(leaves))
+	idx := make(map[string]int, len(leaves))
+	for i, h := range leaves {
+		idx[h] = i
+	}
+
+	for len(level) > 1 {
+		if len(level)%2 == 1 {
+			level = append(level, level[len(level)-1])
+		}
+
+		next := make([][]byte, len(level)/2)
+		for i := 0; i < len(level); i += 2 {
+			sum := sha256.Sum256(sortedConcat(level[i], level[i+1]))
+			next[i/2] = sum[:]
+		}
+
+		for _, h := range leaves {
+			pos := idx[h]
+			sibling := pos + 1
+			if pos%2 == 1 {
+				sibling = pos - 1
+			}
+			proofs[h] = append(proofs[h], hex.EncodeToString(level[sibling]))
+			idx[h] = pos / 2
+		}
+
+		level = next
+	}
+
+	return hex.EncodeToString(level[0]), proofs, nil
+}
+
+// VerifyProof recomputes the Merkle path for fileHash using proof and
+// reports whether it reaches root. It's the inverse of ComputeMerkleRoot and
+// is meant to run client-side against a trusted (e.g. signed) root.
+func VerifyProof(fileHash, root string, proof []string) bool {
+	cur, err := hex.DecodeString(fileHash)
+	if err != nil {
+		return false
+	}
+
+	for _, siblingHex := range proof {
+		sibling, err := hex.DecodeString(siblingHex)
+		if err != nil {
+			return false
+		}
+		sum := sha256.Sum256(sortedConcat(cur, sibling))
+		cur = sum[:]
+	}
+
+	return hex.EncodeToString(cur) == root
+}
+
+func uniqueSortedHashes(files []File) []string {
+	seen := make(map[string]bool, len(files))
+	hashes := make([]string, 0, len(files))
+	for _, f := range files {
+		if seen[f.SHA256] {
+			continue
+		}
+		seen[f.SHA256] = true
+		hashes = append(hashes, f.SHA256)
+	}
+	sort.Strings(hashes)
+	return hashes
+}
+
+func sortedConcat(a, b []byte) []byte {
+	if bytes.Compare(a, b) <= 0 {
+		return append(append([]byte{}, a...), b...)
+	}
+	return append(append([]byte{}, b...), a...)
+}
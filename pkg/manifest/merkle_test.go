@@ -0,0 +1,123 @@
+package manifest
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// sha256Hex builds a stable 32-byte hex string from s. It doesn't need to be
+// a real SHA256 digest, just something ComputeMerkleRoot can decode as one.
+func sha256Hex(s string) string {
+	b := make([]byte, 32)
+	copy(b, s)
+	return hex.EncodeToString(b)
+}
+
+func TestComputeMerkleRoot_EmptyFiles(t *testing.T) {
+	root, proofs, err := ComputeMerkleRoot(nil)
+	if err != nil {
+		t.Fatalf("ComputeMerkleRoot failed: %v", err)
+	}
+	if root != "" {
+		t.Errorf("expected empty root for no files, got %q", root)
+	}
+	if len(proofs) != 0 {
+		t.Errorf("expected no proofs for no files, got %d", len(proofs))
+	}
+}
+
+func TestComputeMerkleRoot_SingleFile(t *testing.T) {
+	files := []File{{Path: "a.txt", SHA256: sha256Hex("a")}}
+
+	root, proofs, err := ComputeMerkleRoot(files)
+	if err != nil {
+		t.Fatalf("ComputeMerkleRoot failed: %v", err)
+	}
+	if root != files[0].SHA256 {
+		t.Errorf("expected single-leaf root to equal the leaf hash, got %q want %q", root, files[0].SHA256)
+	}
+	if len(proofs[files[0].SHA256]) != 0 {
+		t.Errorf("expected an empty proof for a single-leaf tree, got %v", proofs[files[0].SHA256])
+	}
+}
+
+func TestComputeMerkleRoot_ProofsVerify(t *testing.T) {
+	files := []File{
+		{Path: "a.txt", SHA256: sha256Hex("a")},
+		{Path: "b.txt", SHA256: sha256Hex("b")},
+		{Path: "c.txt", SHA256: sha256Hex("c")},
+		{Path: "d.txt", SHA256: sha256Hex("d")},
+		{Path: "e.txt", SHA256: sha256Hex("e")}, // odd count exercises last-leaf duplication
+	}
+
+	root, proofs, err := ComputeMerkleRoot(files)
+	if err != nil {
+		t.Fatalf("ComputeMerkleRoot failed: %v", err)
+	}
+
+	for _, f := range files {
+		proof, ok := proofs[f.SHA256]
+		if !ok {
+			t.Fatalf("no proof for %s", f.Path)
+		}
+		if !VerifyProof(f.SHA256, root, proof) {
+			t.Errorf("VerifyProof failed for %s", f.Path)
+		}
+	}
+}
+
+func TestComputeMerkleRoot_DuplicateHashesShareOneLeaf(t *testing.T) {
+	files := []File{
+		{Path: "a.txt", SHA256: sha256Hex("shared")},
+		{Path: "b.txt", SHA256: sha256Hex("shared")},
+		{Path: "c.txt", SHA256: sha256Hex("other")},
+	}
+
+	root, proofs, err := ComputeMerkleRoot(files)
+	if err != nil {
+		t.Fatalf("ComputeMerkleRoot failed: %v", err)
+	}
+	if len(proofs) != 2 {
+		t.Fatalf("expected 2 unique leaves, got %d", len(proofs))
+	}
+	if !VerifyProof(sha256Hex("shared"), root, proofs[sha256Hex("shared")]) {
+		t.Error("expected the shared hash's proof to verify")
+	}
+}
+
+func TestVerifyProof_RejectsWrongHash(t *testing.T) {
+	files := []File{
+		{Path: "a.txt", SHA256: sha256Hex("a")},
+		{Path: "b.txt", SHA256: sha256Hex("b")},
+		{Path: "c.txt", SHA256: sha256Hex("c")},
+	}
+
+	root, proofs, err := ComputeMerkleRoot(files)
+	if err != nil {
+		t.Fatalf("ComputeMerkleRoot failed: %v", err)
+	}
+
+	if VerifyProof(sha256Hex("not-in-the-tree"), root, proofs[files[0].SHA256]) {
+		t.Error("expected VerifyProof to reject a hash that wasn't in the tree")
+	}
+}
+
+func TestGenerate_PopulatesMerkleRoot(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeRandomFile(t, tmpDir+"/a.bin", 128, 10)
+	writeRandomFile(t, tmpDir+"/b.bin", 128, 11)
+
+	m, err := Generate(tmpDir, "test", "v1", "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	if m.MerkleRoot == "" {
+		t.Fatal("expected Generate to populate MerkleRoot")
+	}
+	for _, f := range m.Files {
+		if !VerifyProof(f.SHA256, m.MerkleRoot, f.MerkleProof) {
+			t.Errorf("MerkleProof for %s does not verify against MerkleRoot", f.Path)
+		}
+	}
+}
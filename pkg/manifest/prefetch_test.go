@@ -0,0 +1,87 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateWithOptions_PrefetchRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"config.json", "tokenizer.json", "model-00001.safetensors", "model-00002.safetensors"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(name), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+	}
+
+	rules := []PrefetchRule{
+		{Pattern: "*.safetensors", Priority: 1},
+		{Pattern: "config.json", Priority: 10},
+		{Pattern: "tokenizer*", Priority: 10, MaxBytes: 4096},
+	}
+
+	m, err := GenerateWithOptions(tmpDir, "test", "v1", "https://example.com", nil, GenerateOptions{PrefetchRules: rules})
+	if err != nil {
+		t.Fatalf("GenerateWithOptions failed: %v", err)
+	}
+
+	if len(m.PrefetchRules) != len(rules) {
+		t.Fatalf("expected PrefetchRules to be preserved, got %d rules", len(m.PrefetchRules))
+	}
+
+	// High-priority rules (config.json, tokenizer*) should resolve before
+	// the low-priority safetensors shards.
+	wantFirstTwo := map[string]bool{"config.json": true, "tokenizer.json": true}
+	for i, path := range m.Prefetch[:2] {
+		if !wantFirstTwo[path] {
+			t.Errorf("expected Prefetch[%d] to be a high-priority match, got %q", i, path)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, p := range m.Prefetch {
+		if seen[p] {
+			t.Errorf("Prefetch contains duplicate entry %q", p)
+		}
+		seen[p] = true
+	}
+	if len(m.Prefetch) != 4 {
+		t.Fatalf("expected all 4 files to match some rule, got %d entries: %v", len(m.Prefetch), m.Prefetch)
+	}
+}
+
+func TestGenerateWithOptions_PrefetchRulesMergeWithLiteralPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"config.json", "README.md"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(name), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+	}
+
+	rules := []PrefetchRule{{Pattern: "config.json", Priority: 1}}
+
+	m, err := GenerateWithOptions(tmpDir, "test", "v1", "https://example.com", []string{"README.md"}, GenerateOptions{PrefetchRules: rules})
+	if err != nil {
+		t.Fatalf("GenerateWithOptions failed: %v", err)
+	}
+
+	want := map[string]bool{"README.md": true, "config.json": true}
+	if len(m.Prefetch) != len(want) {
+		t.Fatalf("expected literal paths and rule matches to merge, got %v", m.Prefetch)
+	}
+	for _, p := range m.Prefetch {
+		if !want[p] {
+			t.Errorf("unexpected entry in Prefetch: %q", p)
+		}
+	}
+}
+
+func TestExpandPrefetchRules_NoMatches(t *testing.T) {
+	files := []File{{Path: "weights.bin"}}
+	rules := []PrefetchRule{{Pattern: "*.json", Priority: 1}}
+
+	got := expandPrefetchRules(files, rules)
+	if len(got) != 0 {
+		t.Errorf("expected no matches, got %v", got)
+	}
+}
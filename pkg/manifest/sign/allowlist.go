@@ -0,0 +1,61 @@
+package sign
+
+import (
+	"bufio"
+	"crypto"
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jakeBringetto/MLArtifactFS/pkg/manifest"
+)
+
+// LoadAllowlist reads a public-key allowlist file consulted at mount time:
+// one hex-encoded ed25519 public key per line. Blank lines and lines
+// starting with "#" are ignored. A manifest signed by any key on the list is
+// considered trusted; this is deliberately simple (no revocation, no key
+// metadata) to match the rest of this package's scope.
+func LoadAllowlist(path string) ([]crypto.PublicKey, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("sign: reading allowlist: %w", err)
+	}
+	defer f.Close()
+
+	var keys []crypto.PublicKey
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		raw, err := hex.DecodeString(line)
+		if err != nil {
+			return nil, fmt.Errorf("sign: allowlist %s line %d: invalid hex: %w", path, lineNo, err)
+		}
+		if len(raw) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("sign: allowlist %s line %d: expected a %d-byte ed25519 key, got %d bytes", path, lineNo, ed25519.PublicKeySize, len(raw))
+		}
+		keys = append(keys, ed25519.PublicKey(raw))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("sign: reading allowlist: %w", err)
+	}
+
+	return keys, nil
+}
+
+// VerifyAny verifies sig against every key in allowlist, succeeding as soon
+// as one key validates. If none validate it returns an error, which is what
+// mount should treat as "reject before mounting".
+func VerifyAny(m *manifest.Manifest, sig []byte, allowlist []crypto.PublicKey) error {
+	for _, pub := range allowlist {
+		if Verify(m, sig, pub) == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("sign: manifest signature did not verify against any key in the allowlist (%d keys tried)", len(allowlist))
+}
@@ -0,0 +1,37 @@
+package sign
+
+import (
+	"crypto"
+	"fmt"
+	"os"
+
+	"github.com/jakeBringetto/MLArtifactFS/pkg/manifest"
+)
+
+// SigPath returns the conventional detached-signature path for a manifest
+// file: "<manifest>.sig".
+func SigPath(manifestPath string) string {
+	return manifestPath + ".sig"
+}
+
+// LoadAndVerify loads the manifest at manifestPath, reads its detached
+// signature from sigPath, and verifies it against allowlist before
+// returning. Callers that need an unverified load should use manifest.Load
+// directly; this exists so mount-time code gets one call that fails closed.
+func LoadAndVerify(manifestPath, sigPath string, allowlist []crypto.PublicKey) (*manifest.Manifest, error) {
+	m, err := manifest.Load(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := os.ReadFile(sigPath)
+	if err != nil {
+		return nil, fmt.Errorf("sign: reading signature: %w", err)
+	}
+
+	if err := VerifyAny(m, sig, allowlist); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
@@ -0,0 +1,86 @@
+// Package sign provides detached, cosign-style signatures over manifests so
+// a mount client can reject a manifest that was tampered with (or that
+// points at swapped storage URLs) before it touches a FUSE mount.
+package sign
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/jakeBringetto/MLArtifactFS/pkg/manifest"
+)
+
+// Canonicalize produces a deterministic JSON encoding of m: Files sorted by
+// Path and Prefetch sorted lexically, with no extra whitespace. Two
+// manifests describing the same artifact produce identical canonical bytes
+// regardless of the order Generate happened to walk the directory in, which
+// is what makes the result safe to hash and sign.
+func Canonicalize(m *manifest.Manifest) ([]byte, error) {
+	clone := *m
+
+	clone.Files = append([]manifest.File(nil), m.Files...)
+	sort.Slice(clone.Files, func(i, j int) bool { return clone.Files[i].Path < clone.Files[j].Path })
+
+	clone.Prefetch = append([]string(nil), m.Prefetch...)
+	sort.Strings(clone.Prefetch)
+
+	return json.Marshal(&clone)
+}
+
+// digest returns the SHA256 of the manifest's canonical form; this is the
+// value Sign and Verify actually operate on.
+func digest(m *manifest.Manifest) ([sha256.Size]byte, error) {
+	canon, err := Canonicalize(m)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(canon), nil
+}
+
+// Sign produces a detached signature over the canonical form of m using key.
+// Both ed25519.PrivateKey and *ecdsa.PrivateKey satisfy crypto.Signer and are
+// supported; any other key type is passed through to key.Sign and will fail
+// unless that implementation understands the opts.Hash passed in.
+func Sign(m *manifest.Manifest, key crypto.Signer) ([]byte, error) {
+	h, err := digest(m)
+	if err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+
+	if edKey, ok := key.(ed25519.PrivateKey); ok {
+		return ed25519.Sign(edKey, h[:]), nil
+	}
+
+	return key.Sign(rand.Reader, h[:], crypto.SHA256)
+}
+
+// Verify checks that sig is a valid signature over the canonical form of m
+// under pub. A nil return means the manifest is authentic and byte-identical
+// to what was signed; any error means it was not.
+func Verify(m *manifest.Manifest, sig []byte, pub crypto.PublicKey) error {
+	h, err := digest(m)
+	if err != nil {
+		return fmt.Errorf("sign: %w", err)
+	}
+
+	switch key := pub.(type) {
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, h[:], sig) {
+			return fmt.Errorf("sign: signature verification failed")
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, h[:], sig) {
+			return fmt.Errorf("sign: signature verification failed")
+		}
+		return nil
+	default:
+		return fmt.Errorf("sign: unsupported public key type %T", pub)
+	}
+}
@@ -0,0 +1,200 @@
+package sign
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jakeBringetto/MLArtifactFS/pkg/manifest"
+)
+
+func testManifest() *manifest.Manifest {
+	return &manifest.Manifest{
+		ArtifactID: "llama-7b",
+		Version:    "v1.0",
+		MountPath:  "/mnt/mlmodel",
+		Prefetch:   []string{"config.json", "tokenizer.json"},
+		Files: []manifest.File{
+			{Path: "weights.bin", URL: "https://example.com/weights.bin", Size: 1024, SHA256: "abc123"},
+			{Path: "config.json", URL: "https://example.com/config.json", Size: 10, SHA256: "def456"},
+		},
+	}
+}
+
+func TestSignVerify_RoundTrip(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	m := testManifest()
+	sig, err := Sign(m, priv)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	if err := Verify(m, sig, pub); err != nil {
+		t.Errorf("expected signature to verify, got error: %v", err)
+	}
+}
+
+func TestVerify_DetectsTampering(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	m := testManifest()
+	sig, err := Sign(m, priv)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	// Swap a URL, as if an attacker redirected a file to their own bucket.
+	m.Files[0].URL = "https://evil.example.com/weights.bin"
+
+	if err := Verify(m, sig, pub); err == nil {
+		t.Error("expected tampered manifest to fail verification")
+	}
+}
+
+func TestVerify_InsensitiveToFileOrder(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	m := testManifest()
+	sig, err := Sign(m, priv)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	reordered := testManifest()
+	reordered.Files[0], reordered.Files[1] = reordered.Files[1], reordered.Files[0]
+
+	if err := Verify(reordered, sig, pub); err != nil {
+		t.Errorf("expected canonicalization to make file order irrelevant, got error: %v", err)
+	}
+}
+
+func TestVerifyAny_RejectsWhenNoKeyMatches(t *testing.T) {
+	_, priv1, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pub2, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	m := testManifest()
+	sig, err := Sign(m, priv1)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	if err := VerifyAny(m, sig, []crypto.PublicKey{pub2}); err == nil {
+		t.Error("expected VerifyAny to fail when the signing key is not in the allowlist")
+	}
+}
+
+func TestVerifyAny_AcceptsAnyMatchingKey(t *testing.T) {
+	pub1, priv1, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pub2, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	m := testManifest()
+	sig, err := Sign(m, priv1)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	if err := VerifyAny(m, sig, []crypto.PublicKey{pub2, pub1}); err != nil {
+		t.Errorf("expected VerifyAny to succeed when one key in the allowlist matches, got: %v", err)
+	}
+}
+
+func TestLoadAllowlist(t *testing.T) {
+	pub1, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "allowlist.txt")
+	contents := "# trusted signers\n" + hex.EncodeToString(pub1) + "\n\n"
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("failed to write allowlist: %v", err)
+	}
+
+	keys, err := LoadAllowlist(path)
+	if err != nil {
+		t.Fatalf("LoadAllowlist failed: %v", err)
+	}
+	if len(keys) != 1 {
+		t.Fatalf("expected 1 key, got %d", len(keys))
+	}
+	if !keys[0].(ed25519.PublicKey).Equal(pub1) {
+		t.Error("loaded key does not match the original public key")
+	}
+}
+
+func TestLoadAllowlist_RejectsInvalidEntry(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "allowlist.txt")
+	if err := os.WriteFile(path, []byte("not-valid-hex!!\n"), 0644); err != nil {
+		t.Fatalf("failed to write allowlist: %v", err)
+	}
+
+	if _, err := LoadAllowlist(path); err == nil {
+		t.Error("expected LoadAllowlist to reject an invalid entry")
+	}
+}
+
+func TestLoadAndVerify_RoundTrip(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	manifestPath := filepath.Join(tmpDir, "manifest.json")
+
+	m := testManifest()
+	data, err := manifest.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if err := os.WriteFile(manifestPath, data, 0644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	// Sign the manifest as it will be loaded from disk, not the in-memory
+	// struct, since JSON round-tripping and canonicalization must agree.
+	loaded, err := manifest.Load(manifestPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	sig, err := Sign(loaded, priv)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	sigPath := SigPath(manifestPath)
+	if err := os.WriteFile(sigPath, sig, 0644); err != nil {
+		t.Fatalf("failed to write signature: %v", err)
+	}
+
+	if _, err := LoadAndVerify(manifestPath, sigPath, []crypto.PublicKey{pub}); err != nil {
+		t.Errorf("LoadAndVerify failed: %v", err)
+	}
+}